@@ -0,0 +1,245 @@
+package prim
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+
+	"github.com/gostdlib/concurrency/goroutines"
+	"github.com/gostdlib/concurrency/goroutines/limited"
+	"github.com/gostdlib/internals/otel/span"
+)
+
+// Channel reads values from "in", applies Mutator "mut" to each using the goroutines Pool
+// "p", and writes the results to "out" as they complete, in whatever order they finish in.
+// It closes "out" once "in" is closed and all in-flight work has finished. If p == nil, p
+// becomes a limited.Pool using up to runtime.NumCPU(). Errors are aggregated the same way
+// as Slice/ResultSlice; pass WithFailFast or WithCollectAllErrors in opts to change that
+// behavior. Use ChannelOrdered instead if results must come out in the order "in" produced
+// them.
+func Channel[T, R any](ctx context.Context, in <-chan T, out chan<- R, mut Mutator[T, R], p goroutines.Pool, opts ...SliceOpt) error {
+	spanner := span.Get(ctx)
+	o := buildSliceOptions(opts)
+
+	if p == nil {
+		var err error
+		p, err = limited.New("", runtime.NumCPU())
+		if err != nil {
+			spanner.Error(err)
+			return err
+		}
+		defer p.Close()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sink := newErrSink(o.collectAll, o.failFast)
+	var cancelOnce sync.Once
+	var idx int
+
+readLoop:
+	for {
+		select {
+		case <-ctx.Done():
+			break readLoop
+		case v, ok := <-in:
+			if !ok {
+				break readLoop
+			}
+			i := idx
+			idx++
+
+			err := p.Submit(
+				ctx,
+				func(ctx context.Context) {
+					r, err := mut(ctx, v)
+					if err != nil {
+						sink.record(i, err)
+						if o.failFast && !errors.Is(err, context.Canceled) {
+							cancelOnce.Do(cancel)
+						}
+						return
+					}
+					select {
+					case out <- r:
+					case <-ctx.Done():
+					}
+				},
+				o.subOpts...,
+			)
+			if err != nil {
+				sink.record(i, err)
+				break readLoop
+			}
+		}
+	}
+	p.Wait()
+	close(out)
+
+	if err := sink.err(); err != nil {
+		spanner.Error(err)
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// ChannelOrdered behaves like Channel, but writes to "out" in the same order "in" produced
+// the corresponding values. Results that finish out of order are held in a reorder buffer
+// until every earlier result has been emitted; once the buffer is full, the Mutator
+// goroutine holding the out-of-order result blocks, which in turn stalls the Pool and
+// therefore reading from "in", providing back-pressure. The buffer size defaults to
+// max(1, runtime.NumCPU()*reorderBufferTuning) slots; pass WithReorderBuffer to override it.
+func ChannelOrdered[T, R any](ctx context.Context, in <-chan T, out chan<- R, mut Mutator[T, R], p goroutines.Pool, opts ...SliceOpt) error {
+	spanner := span.Get(ctx)
+	o := buildSliceOptions(opts)
+
+	if p == nil {
+		var err error
+		p, err = limited.New("", runtime.NumCPU())
+		if err != nil {
+			spanner.Error(err)
+			return err
+		}
+		defer p.Close()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sink := newErrSink(o.collectAll, o.failFast)
+	var cancelOnce sync.Once
+	var zero R
+	buf := newReorderBuffer[R](o.resolvedReorderBuffer())
+
+	emit := func(r R) {
+		select {
+		case out <- r:
+		case <-ctx.Done():
+		}
+	}
+
+	var idx int
+readLoop:
+	for {
+		select {
+		case <-ctx.Done():
+			break readLoop
+		case v, ok := <-in:
+			if !ok {
+				break readLoop
+			}
+			i := idx
+			idx++
+
+			err := p.Submit(
+				ctx,
+				func(ctx context.Context) {
+					r, err := mut(ctx, v)
+					if err != nil {
+						sink.record(i, err)
+						if o.failFast && !errors.Is(err, context.Canceled) {
+							cancelOnce.Do(cancel)
+						}
+					}
+					buf.put(i, r, err == nil)
+					buf.drain(emit)
+				},
+				o.subOpts...,
+			)
+			if err != nil {
+				sink.record(i, err)
+				// p.Submit never ran the closure that would have called buf.put/drain
+				// for "i", so mark it skipped here or later results would be stranded
+				// behind it forever.
+				buf.put(i, zero, false)
+				buf.drain(emit)
+				break readLoop
+			}
+		}
+	}
+	p.Wait()
+	buf.drain(emit)
+	close(out)
+
+	if err := sink.err(); err != nil {
+		spanner.Error(err)
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// reorderBuffer holds completed results keyed by the sequence number "in" produced them in,
+// releasing them to a drain callback in order as each one becomes the next one due. It
+// blocks producers once too many results are waiting for an earlier one to arrive, bounding
+// memory use and providing back-pressure.
+type reorderBuffer[R any] struct {
+	mu    sync.Mutex
+	cond  *sync.Cond
+	items map[int]R
+	// skip marks sequence numbers whose Mutator errored, so they have no value to emit
+	// but still need to be accounted for when advancing "next".
+	skip  map[int]bool
+	next  int
+	limit int
+}
+
+func newReorderBuffer[R any](limit int) *reorderBuffer[R] {
+	b := &reorderBuffer[R]{
+		items: make(map[int]R),
+		skip:  make(map[int]bool),
+		limit: limit,
+	}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// put stores the result for sequence number "i". If ok is false, there is no value to
+// store; "i" is simply marked as skipped. put blocks while "i" is too far ahead of the
+// next sequence number due to be drained.
+func (b *reorderBuffer[R]) put(i int, r R, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i-b.next >= b.limit {
+		b.cond.Wait()
+	}
+	if ok {
+		b.items[i] = r
+	} else {
+		b.skip[i] = true
+	}
+}
+
+// drain calls "emit" for every contiguous result already buffered starting at the next
+// sequence number due, advancing past any sequence numbers marked skipped. It holds b.mu
+// for the duration of each emit call, so that two overlapping drain calls (e.g. one from
+// the completion of index N, another from N+1 finishing first and racing in) can never
+// emit out of sequence order.
+func (b *reorderBuffer[R]) drain(emit func(R)) {
+	b.mu.Lock()
+	defer b.cond.Broadcast()
+	defer b.mu.Unlock()
+
+	for {
+		if r, ok := b.items[b.next]; ok {
+			delete(b.items, b.next)
+			b.next++
+			emit(r)
+			continue
+		}
+		if b.skip[b.next] {
+			delete(b.skip, b.next)
+			b.next++
+			continue
+		}
+		return
+	}
+}
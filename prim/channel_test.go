@@ -0,0 +1,91 @@
+package prim
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// TestChannelAppliesMutatorConcurrently feeds Channel a sequence of inputs and checks that
+// every one comes out the other side with the Mutator applied, regardless of the order
+// they're received in.
+func TestChannelAppliesMutatorConcurrently(t *testing.T) {
+	const n = 50
+
+	in := make(chan int)
+	out := make(chan int)
+
+	go func() {
+		defer close(in)
+		for i := 0; i < n; i++ {
+			in <- i
+		}
+	}()
+
+	mut := func(ctx context.Context, i int) (int, error) { return i * 2, nil }
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- Channel(context.Background(), in, out, mut, nil)
+	}()
+
+	got := make(map[int]bool, n)
+	for v := range out {
+		got[v] = true
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("Channel() = %v, want nil", err)
+	}
+	if len(got) != n {
+		t.Fatalf("got %d distinct results, want %d: %v", len(got), n, got)
+	}
+	for i := 0; i < n; i++ {
+		if !got[i*2] {
+			t.Fatalf("missing result %d (mut(%d)) in output: %v", i*2, i, got)
+		}
+	}
+}
+
+// TestChannelOrderedPreservesOrder feeds ChannelOrdered a sequence whose Mutator
+// completions finish in jittered, non-monotonic order and asserts "out" still receives
+// results in the order "in" produced them.
+func TestChannelOrderedPreservesOrder(t *testing.T) {
+	const n = 200
+
+	in := make(chan int)
+	out := make(chan int)
+
+	go func() {
+		defer close(in)
+		for i := 0; i < n; i++ {
+			in <- i
+		}
+	}()
+
+	mut := func(ctx context.Context, i int) (int, error) {
+		time.Sleep(time.Duration(rand.Intn(500)) * time.Microsecond)
+		return i, nil
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- ChannelOrdered(context.Background(), in, out, mut, nil)
+	}()
+
+	got := make([]int, 0, n)
+	for v := range out {
+		got = append(got, v)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("ChannelOrdered() = %v, want nil", err)
+	}
+	if len(got) != n {
+		t.Fatalf("got %d results, want %d", len(got), n)
+	}
+	for i, v := range got {
+		if v != i {
+			t.Fatalf("out of order at position %d: got %d, want %d (full: %v)", i, v, i, got)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package prim
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gostdlib/concurrency/goroutines/limited"
+)
+
+// TestWithChunkSizeMatchesUnchunkedOutput ensures chunked dispatch preserves index
+// positions and produces the same results as the default one-task-per-element dispatch.
+func TestWithChunkSizeMatchesUnchunkedOutput(t *testing.T) {
+	s := make([]int, 37)
+	for i := range s {
+		s[i] = i
+	}
+	mut := func(ctx context.Context, i int) (int, error) { return i * i, nil }
+
+	unchunked, err := ResultSlice(context.Background(), s, mut, nil)
+	if err != nil {
+		t.Fatalf("ResultSlice() = %v, want nil", err)
+	}
+
+	for _, size := range []int{1, 4, 5, 37, 100} {
+		chunked, err := ResultSlice(context.Background(), s, mut, nil, WithChunkSize(size))
+		if err != nil {
+			t.Fatalf("ResultSlice(WithChunkSize(%d)) = %v, want nil", size, err)
+		}
+		if len(chunked) != len(unchunked) {
+			t.Fatalf("WithChunkSize(%d): len(chunked) = %d, want %d", size, len(chunked), len(unchunked))
+		}
+		for i := range unchunked {
+			if chunked[i] != unchunked[i] {
+				t.Fatalf("WithChunkSize(%d): chunked[%d] = %d, want %d", size, i, chunked[i], unchunked[i])
+			}
+		}
+	}
+}
+
+// TestWithChunkSizeAutoTunes ensures that WithChunkSize(0) (the documented auto-tune
+// trigger) still dispatches every element rather than silently dropping any of them.
+func TestWithChunkSizeAutoTunes(t *testing.T) {
+	s := make([]int, 1000)
+	for i := range s {
+		s[i] = i
+	}
+	mut := func(ctx context.Context, i int) (int, error) { return i, nil }
+
+	got, err := ResultSlice(context.Background(), s, mut, nil, WithChunkSize(0))
+	if err != nil {
+		t.Fatalf("ResultSlice(WithChunkSize(0)) = %v, want nil", err)
+	}
+	for i, v := range got {
+		if v != i {
+			t.Fatalf("got[%d] = %d, want %d", i, v, i)
+		}
+	}
+}
+
+// TestWithChunkSizeHonorsCancelBetweenAndWithinChunks uses a single-worker Pool so
+// dispatch is deterministic: a Mutator that cancels ctx at index 4, the last element of
+// the first chunk (0-4), lets that chunk run all 5 elements, but no element of any later
+// chunk may run: each is either never submitted (ctx.Err() checked between chunks) or
+// aborted before its first element (ctx.Err() checked between elements within a chunk).
+func TestWithChunkSizeHonorsCancelBetweenAndWithinChunks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p, err := limited.New("", 1)
+	if err != nil {
+		t.Fatalf("limited.New() = %v, want nil", err)
+	}
+	defer p.Close()
+
+	s := make([]int, 20)
+	var ran int32
+
+	mut := func(ctx context.Context, i int) (int, error) {
+		atomic.AddInt32(&ran, 1)
+		if i == 4 {
+			cancel()
+		}
+		return i, nil
+	}
+
+	_ = Slice(ctx, s, mut, p, WithChunkSize(5))
+
+	if got := atomic.LoadInt32(&ran); got != 5 {
+		t.Fatalf("ran = %d Mutator calls, want exactly 5: the first chunk (0-4) runs up to the cancel at its last index 4, and no later chunk runs any element", got)
+	}
+}
@@ -0,0 +1,136 @@
+package prim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// IndexedError pairs an error returned by a Mutator with the index in the input slice
+// that produced it.
+type IndexedError struct {
+	// Index is the position in the input slice whose Mutator call returned Err.
+	Index int
+	// Err is the error the Mutator call at Index returned.
+	Err error
+}
+
+// Error implements error.
+func (i IndexedError) Error() string {
+	return fmt.Sprintf("index %d: %s", i.Index, i.Err)
+}
+
+// Unwrap returns the underlying error so errors.Is and errors.As can see through IndexedError.
+func (i IndexedError) Unwrap() error {
+	return i.Err
+}
+
+// SliceError is returned by Slice and ResultSlice when WithCollectAllErrors is used and one
+// or more Mutator calls fail. It holds every failure, paired with the index of the input
+// slice that produced it.
+type SliceError struct {
+	// Errs holds one IndexedError per failed Mutator call, sorted by Index.
+	Errs []IndexedError
+}
+
+// Error implements error.
+func (s *SliceError) Error() string {
+	if len(s.Errs) == 1 {
+		return s.Errs[0].Error()
+	}
+	return fmt.Sprintf("%d errors occurred, first: %s", len(s.Errs), s.Errs[0].Error())
+}
+
+// Unwrap returns every error in Errs, so that errors.Is and errors.As (and errors.Join
+// callers) can inspect each underlying error.
+func (s *SliceError) Unwrap() []error {
+	errs := make([]error, 0, len(s.Errs))
+	for _, ie := range s.Errs {
+		errs = append(errs, ie.Err)
+	}
+	return errs
+}
+
+// errSink collects Mutator errors for Slice and ResultSlice. By default it keeps the first
+// real error recorded (a context.Canceled is only ever a placeholder and never displaces
+// one). When collectAll is set, it instead gathers every error into a *SliceError.
+type errSink struct {
+	collectAll bool
+	// failFast records whether WithFailFast is in effect, so record can drop the
+	// context.Canceled noise it induces in Mutators still in flight.
+	failFast bool
+
+	// used when !collectAll.
+	ptr atomic.Pointer[error]
+
+	// used when collectAll.
+	mu   sync.Mutex
+	errs []IndexedError
+}
+
+func newErrSink(collectAll, failFast bool) *errSink {
+	return &errSink{collectAll: collectAll, failFast: failFast}
+}
+
+// record stores "err" for index "i". It is safe to call concurrently.
+func (s *errSink) record(i int, err error) {
+	if err == nil {
+		return
+	}
+	if s.failFast && errors.Is(err, context.Canceled) {
+		// WithFailFast cancels the shared context as soon as a real error is found.
+		// Every Mutator still running at that point will typically observe and return
+		// that cancellation, often wrapped (e.g. fmt.Errorf("io: %w", ctx.Err())). It
+		// carries no information beyond "fail-fast fired" and would otherwise dilute
+		// or mask the real error(s) that triggered it, so it's dropped here.
+		return
+	}
+	if s.collectAll {
+		s.mu.Lock()
+		s.errs = append(s.errs, IndexedError{Index: i, Err: err})
+		s.mu.Unlock()
+		return
+	}
+	applyErr(&s.ptr, err)
+}
+
+// err returns the accumulated error, or nil if nothing was recorded.
+func (s *errSink) err() error {
+	if s.collectAll {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if len(s.errs) == 0 {
+			return nil
+		}
+		sort.Slice(s.errs, func(i, j int) bool { return s.errs[i].Index < s.errs[j].Index })
+		return &SliceError{Errs: s.errs}
+	}
+	if p := s.ptr.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// applyErr stores the first real (non-context.Canceled) error seen in ptr. A
+// context.Canceled is only ever stored as a placeholder when nothing else has been
+// recorded yet, and is itself replaced by the first real error that follows; once a real
+// error is stored, it is never overwritten by anything that arrives afterward.
+func applyErr(ptr *atomic.Pointer[error], err error) {
+	if errors.Is(err, context.Canceled) {
+		ptr.CompareAndSwap(nil, &err)
+		return
+	}
+	wrapped := fmt.Errorf("%w", err)
+	for {
+		existing := ptr.Load()
+		if existing != nil && !errors.Is(*existing, context.Canceled) {
+			return
+		}
+		if ptr.CompareAndSwap(existing, &wrapped) {
+			return
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package prim
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestWithCollectAllErrorsAggregatesAndSorts covers chunk0-2's core ask: multiple
+// independent Mutator failures at different indices are all reported, sorted by index,
+// and composable with errors.Is/errors.As via SliceError.Unwrap.
+func TestWithCollectAllErrorsAggregatesAndSorts(t *testing.T) {
+	err1 := errors.New("err at index 1")
+	err3 := errors.New("err at index 3")
+
+	mut := func(ctx context.Context, i int) (int, error) {
+		switch i {
+		case 1:
+			return 0, err1
+		case 3:
+			return 0, err3
+		default:
+			return i, nil
+		}
+	}
+
+	err := Slice(context.Background(), []int{0, 1, 2, 3, 4}, mut, nil, WithCollectAllErrors())
+
+	var sliceErr *SliceError
+	if !errors.As(err, &sliceErr) {
+		t.Fatalf("Slice(WithCollectAllErrors()) = %v, want *SliceError", err)
+	}
+	if len(sliceErr.Errs) != 2 {
+		t.Fatalf("SliceError.Errs = %d entries, want 2: %v", len(sliceErr.Errs), sliceErr.Errs)
+	}
+	if sliceErr.Errs[0].Index != 1 || sliceErr.Errs[1].Index != 3 {
+		t.Fatalf("SliceError.Errs indexes = [%d, %d], want [1, 3] (sorted by index)", sliceErr.Errs[0].Index, sliceErr.Errs[1].Index)
+	}
+	if !errors.Is(err, err1) {
+		t.Fatalf("errors.Is(err, err1) = false, want true via SliceError.Unwrap")
+	}
+	if !errors.Is(err, err3) {
+		t.Fatalf("errors.Is(err, err3) = false, want true via SliceError.Unwrap")
+	}
+}
@@ -2,9 +2,9 @@ package prim
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"runtime"
-	"sync/atomic"
+	"sync"
 
 	"github.com/gostdlib/concurrency/goroutines"
 	"github.com/gostdlib/concurrency/goroutines/limited"
@@ -15,43 +15,135 @@ import (
 // T and R can be the same type.
 type Mutator[T, R any] func(context.Context, T) (R, error)
 
-// Slice applies Mutator "m" to each element in "s" using the goroutines Pool
-// "p". If p == nil, p becomes a limited.Pool using up to runtime.NumCPU().
-// Errors will be returned, but will not stop this from completing.
-// Values at the position that return an error will remain unchanged.
-func Slice[T any](ctx context.Context, s []T, mut Mutator[T, T], p goroutines.Pool, subOpts ...goroutines.SubmitOption) error {
-	spanner := span.Get(ctx)
+// sliceOptions is the configuration built from the SliceOpt values passed to
+// Slice, ResultSlice, Channel, ChannelOrdered and their *WithContext variants.
+type sliceOptions struct {
+	failFast      bool
+	collectAll    bool
+	chunked       bool
+	chunkSize     int
+	reorderBuffer int
+	subOpts       []goroutines.SubmitOption
+}
 
-	if len(s) == 0 {
-		return nil
+// chunkSizeTuning is the divisor "k" used to auto-tune the chunk size when WithChunkSize
+// is given a size <= 0: len(s) / (chunkSizeTuning * runtime.NumCPU()).
+const chunkSizeTuning = 4
+
+// resolvedChunkSize returns the number of elements to hand to each Pool task for an input
+// of length n. Without WithChunkSize, every element gets its own task (chunk size 1).
+func (o sliceOptions) resolvedChunkSize(n int) int {
+	if !o.chunked {
+		return 1
 	}
+	if o.chunkSize > 0 {
+		return o.chunkSize
+	}
+	size := n / (chunkSizeTuning * runtime.NumCPU())
+	if size < 1 {
+		size = 1
+	}
+	return size
+}
 
-	if p == nil {
-		var err error
-		p, err = limited.New("", runtime.NumCPU())
-		if err != nil {
-			spanner.Error(err)
-			return err
-		}
-		defer p.Close()
+// reorderBufferTuning is the multiplier "k" used to auto-tune ChannelOrdered's reorder
+// buffer when WithReorderBuffer is given a size <= 0: runtime.NumCPU() * reorderBufferTuning.
+const reorderBufferTuning = 2
+
+// resolvedReorderBuffer returns the number of out-of-order results ChannelOrdered may hold
+// before it applies back-pressure.
+func (o sliceOptions) resolvedReorderBuffer() int {
+	if o.reorderBuffer > 0 {
+		return o.reorderBuffer
+	}
+	n := runtime.NumCPU() * reorderBufferTuning
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// SliceOpt is an optional argument to Slice, ResultSlice and their *WithContext variants.
+type SliceOpt func(*sliceOptions)
+
+// WithFailFast cancels the context passed to every pending and still-running Mutator call
+// as soon as one Mutator returns an error, and stops submitting further work to the Pool.
+// The error returned by Slice/ResultSlice is the first error encountered, not the
+// context.Canceled that fail-fast induces in the Mutators still in flight.
+func WithFailFast() SliceOpt {
+	return func(o *sliceOptions) { o.failFast = true }
+}
+
+// WithCollectAllErrors makes Slice/ResultSlice return a *SliceError holding every Mutator
+// error, paired with the index that produced it, instead of only the first one.
+func WithCollectAllErrors() SliceOpt {
+	return func(o *sliceOptions) { o.collectAll = true }
+}
+
+// WithSubmitOptions passes goroutines.SubmitOption values through to every p.Submit() call
+// made on behalf of the caller.
+func WithSubmitOptions(subOpts ...goroutines.SubmitOption) SliceOpt {
+	return func(o *sliceOptions) { o.subOpts = subOpts }
+}
+
+// WithChunkSize partitions the input into contiguous ranges of "n" elements and submits one
+// Pool task per range instead of one per element, amortizing per-item goroutine overhead
+// when the Mutator is cheap and the input is large. If n <= 0, the chunk size is auto-tuned
+// to max(1, len(s)/(chunkSizeTuning*runtime.NumCPU())).
+func WithChunkSize(n int) SliceOpt {
+	return func(o *sliceOptions) {
+		o.chunked = true
+		o.chunkSize = n
 	}
+}
 
-	ptr := atomic.Pointer[error]{}
+// WithReorderBuffer bounds how many out-of-order results ChannelOrdered may hold while
+// waiting for earlier results to complete, before it stops reading from "in" to apply
+// back-pressure. If n <= 0, the bound is auto-tuned to
+// max(1, runtime.NumCPU()*reorderBufferTuning). It has no effect on Slice, ResultSlice or
+// Channel.
+func WithReorderBuffer(n int) SliceOpt {
+	return func(o *sliceOptions) { o.reorderBuffer = n }
+}
 
-	for i := 0; i < len(s); i++ {
-		i := i
+func buildSliceOptions(opts []SliceOpt) sliceOptions {
+	o := sliceOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// dispatchChunks splits [0, n) into contiguous ranges of "chunkSize" elements (the final
+// range may be shorter) and submits one Pool task per range. Each task runs "process"
+// synchronously over its range, checking ctx.Err() between elements so a canceled context
+// aborts a chunk partway through. It also checks ctx.Err() between chunks, so no further
+// tasks are submitted once canceled. It returns the first error from p.Submit, if any;
+// errors from "process" itself are the caller's responsibility to collect.
+func dispatchChunks(ctx context.Context, p goroutines.Pool, n, chunkSize int, subOpts []goroutines.SubmitOption, process func(ctx context.Context, i int)) error {
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
 
+	for start := 0; start < n; start += chunkSize {
 		if ctx.Err() != nil {
-			return ctx.Err()
+			return nil
+		}
+
+		end := start + chunkSize
+		if end > n {
+			end = n
 		}
+		start, end := start, end
 
 		err := p.Submit(
 			ctx,
 			func(ctx context.Context) {
-				var err error
-				s[i], err = mut(ctx, s[i])
-				if err != nil {
-					applyErr(&ptr, err)
+				for i := start; i < end; i++ {
+					if ctx.Err() != nil {
+						return
+					}
+					process(ctx, i)
 				}
 			},
 			subOpts...,
@@ -60,12 +152,89 @@ func Slice[T any](ctx context.Context, s []T, mut Mutator[T, T], p goroutines.Po
 			return err
 		}
 	}
+	return nil
+}
+
+// Slice applies Mutator "m" to each element in "s" using the goroutines Pool
+// "p". If p == nil, p becomes a limited.Pool using up to runtime.NumCPU().
+// Errors will be returned, but will not stop this from completing, unless
+// WithFailFast is passed in opts. Values at the position that return an error
+// will remain unchanged. By default one Pool task is submitted per element;
+// pass WithChunkSize to submit one task per contiguous range instead.
+//
+// BREAKING: the trailing argument used to be "subOpts ...goroutines.SubmitOption";
+// it is now "opts ...SliceOpt". Callers passing goroutines.SubmitOption values directly
+// must switch to WithSubmitOptions(subOpts...).
+func Slice[T any](ctx context.Context, s []T, mut Mutator[T, T], p goroutines.Pool, opts ...SliceOpt) error {
+	_, slice := SliceWithContext[T](ctx)
+	return slice(s, mut, p, opts...)
+}
+
+// SliceWithContext returns a context derived from ctx and a Slice-like function bound to
+// that context. When WithFailFast is passed to the returned function and a Mutator
+// returns an error, the derived context is canceled early, so callers can plumb it into
+// their own I/O; either way, it is always canceled once the returned function returns, to
+// release its resources. This mirrors the pattern used by golang.org/x/sync/errgroup.WithContext,
+// whose Group.Wait() unconditionally cancels its derived context too. The returned function
+// is meant to be called once per SliceWithContext call, the same as errgroup.Group.Wait().
+func SliceWithContext[T any](ctx context.Context) (context.Context, func(s []T, mut Mutator[T, T], p goroutines.Pool, opts ...SliceOpt) error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	fn := func(s []T, mut Mutator[T, T], p goroutines.Pool, opts ...SliceOpt) error {
+		return sliceCtx(ctx, cancel, s, mut, p, buildSliceOptions(opts))
+	}
+	return ctx, fn
+}
+
+func sliceCtx[T any](ctx context.Context, cancel context.CancelFunc, s []T, mut Mutator[T, T], p goroutines.Pool, o sliceOptions) error {
+	// Release ctx's resources as soon as this call is done, whether or not fail-fast
+	// ever fired, so a long-lived parent context doesn't accumulate a child for every
+	// Slice call made against it. Mirrors errgroup.Group.Wait() unconditionally calling
+	// its derived cancel.
+	defer cancel()
+
+	spanner := span.Get(ctx)
+
+	if len(s) == 0 {
+		return nil
+	}
+
+	if p == nil {
+		var err error
+		p, err = limited.New("", runtime.NumCPU())
+		if err != nil {
+			spanner.Error(err)
+			return err
+		}
+		defer p.Close()
+	}
+
+	sink := newErrSink(o.collectAll, o.failFast)
+	var cancelOnce sync.Once
+
+	dispatchErr := dispatchChunks(ctx, p, len(s), o.resolvedChunkSize(len(s)), o.subOpts, func(ctx context.Context, i int) {
+		v, err := mut(ctx, s[i])
+		if err != nil {
+			sink.record(i, err)
+			if o.failFast && !errors.Is(err, context.Canceled) {
+				cancelOnce.Do(cancel)
+			}
+			return
+		}
+		s[i] = v
+	})
 	p.Wait()
 
-	errPtr := ptr.Load()
-	if errPtr != nil {
-		spanner.Error(*errPtr)
-		return *errPtr
+	if err := sink.err(); err != nil {
+		spanner.Error(err)
+		return err
+	}
+	if dispatchErr != nil {
+		spanner.Error(dispatchErr)
+		return dispatchErr
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
 	}
 	return nil
 }
@@ -73,9 +242,42 @@ func Slice[T any](ctx context.Context, s []T, mut Mutator[T, T], p goroutines.Po
 // ResultSlice takes values in slice "s" and applies Mutator "m" to get a new result slice []R.
 // Slice "s" is not mutated. This allows you to have a returns slice of a different type or
 // simply to leave the passed slice untouched.
-// Errors will be returned, but will not stop this from completing. Values at the
-// position that return an error will be the zero value for the R type.
-func ResultSlice[T, R any](ctx context.Context, s []T, mut Mutator[T, R], p goroutines.Pool, subOpts ...goroutines.SubmitOption) ([]R, error) {
+// Errors will be returned, but will not stop this from completing, unless WithFailFast is
+// passed in opts. Values at the position that return an error will be the zero value for
+// the R type. By default one Pool task is submitted per element; pass WithChunkSize to
+// submit one task per contiguous range instead.
+//
+// BREAKING: the trailing argument used to be "subOpts ...goroutines.SubmitOption";
+// it is now "opts ...SliceOpt". Callers passing goroutines.SubmitOption values directly
+// must switch to WithSubmitOptions(subOpts...).
+func ResultSlice[T, R any](ctx context.Context, s []T, mut Mutator[T, R], p goroutines.Pool, opts ...SliceOpt) ([]R, error) {
+	_, resultSlice := ResultSliceWithContext[T, R](ctx)
+	return resultSlice(s, mut, p, opts...)
+}
+
+// ResultSliceWithContext returns a context derived from ctx and a ResultSlice-like function
+// bound to that context. When WithFailFast is passed to the returned function and a Mutator
+// returns an error, the derived context is canceled early, so callers can plumb it into
+// their own I/O; either way, it is always canceled once the returned function returns, to
+// release its resources. This mirrors the pattern used by golang.org/x/sync/errgroup.WithContext,
+// whose Group.Wait() unconditionally cancels its derived context too. The returned function
+// is meant to be called once per ResultSliceWithContext call, the same as errgroup.Group.Wait().
+func ResultSliceWithContext[T, R any](ctx context.Context) (context.Context, func(s []T, mut Mutator[T, R], p goroutines.Pool, opts ...SliceOpt) ([]R, error)) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	fn := func(s []T, mut Mutator[T, R], p goroutines.Pool, opts ...SliceOpt) ([]R, error) {
+		return resultSliceCtx(ctx, cancel, s, mut, p, buildSliceOptions(opts))
+	}
+	return ctx, fn
+}
+
+func resultSliceCtx[T, R any](ctx context.Context, cancel context.CancelFunc, s []T, mut Mutator[T, R], p goroutines.Pool, o sliceOptions) ([]R, error) {
+	// Release ctx's resources as soon as this call is done, whether or not fail-fast
+	// ever fired, so a long-lived parent context doesn't accumulate a child for every
+	// ResultSlice call made against it. Mirrors errgroup.Group.Wait() unconditionally
+	// calling its derived cancel.
+	defer cancel()
+
 	spanner := span.Get(ctx)
 
 	if len(s) == 0 {
@@ -95,52 +297,33 @@ func ResultSlice[T, R any](ctx context.Context, s []T, mut Mutator[T, R], p goro
 		defer p.Close()
 	}
 
-	ptr := atomic.Pointer[error]{}
+	sink := newErrSink(o.collectAll, o.failFast)
+	var cancelOnce sync.Once
 	results := make([]R, len(s))
-	for i := 0; i < len(s); i++ {
-		i := i
 
-		if ctx.Err() != nil {
-			return nil, ctx.Err()
+	dispatchErr := dispatchChunks(ctx, p, len(s), o.resolvedChunkSize(len(s)), o.subOpts, func(ctx context.Context, i int) {
+		r, err := mut(ctx, s[i])
+		if err != nil {
+			sink.record(i, err)
+			if o.failFast && !errors.Is(err, context.Canceled) {
+				cancelOnce.Do(cancel)
+			}
+			return
 		}
-
-		p.Submit(
-			ctx,
-			func(ctx context.Context) {
-				var err error
-				results[i], err = mut(ctx, s[i])
-				if err != nil {
-					applyErr(&ptr, err)
-				}
-			},
-			subOpts...,
-		)
-	}
+		results[i] = r
+	})
 	p.Wait()
 
-	errPtr := ptr.Load()
-	if errPtr != nil {
-		spanner.Error(*errPtr)
-		return results, *errPtr
+	if err := sink.err(); err != nil {
+		spanner.Error(err)
+		return results, err
 	}
-	return results, nil
-}
-
-func applyErr(ptr *atomic.Pointer[error], err error) {
-	for {
-		existing := ptr.Load()
-		if existing == nil {
-			if ptr.CompareAndSwap(nil, &err) {
-				return
-			}
-		} else {
-			if err == context.Canceled {
-				return
-			}
-			err = fmt.Errorf("%w", err)
-			if ptr.CompareAndSwap(existing, &err) {
-				return
-			}
-		}
+	if dispatchErr != nil {
+		spanner.Error(dispatchErr)
+		return results, dispatchErr
+	}
+	if ctx.Err() != nil {
+		return results, ctx.Err()
 	}
+	return results, nil
 }
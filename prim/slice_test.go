@@ -0,0 +1,75 @@
+package prim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestFailFastWrappedCancelDoesNotMaskRealError ensures that a Mutator which wraps
+// ctx.Err() after WithFailFast fires (a very common io-error pattern) never displaces the
+// real error that triggered the cancellation, whether collecting a single error or all of
+// them with WithCollectAllErrors.
+func TestFailFastWrappedCancelDoesNotMaskRealError(t *testing.T) {
+	wantErr := errors.New("real failure")
+
+	mut := func(ctx context.Context, i int) (int, error) {
+		if i == 0 {
+			return 0, wantErr
+		}
+		<-ctx.Done()
+		return 0, fmt.Errorf("io: %w", ctx.Err())
+	}
+
+	s := make([]int, 50)
+	for i := range s {
+		s[i] = i
+	}
+
+	err := Slice(context.Background(), s, mut, nil, WithFailFast())
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("Slice(WithFailFast()) = %v, want wrapping %v", err, wantErr)
+	}
+
+	err = Slice(context.Background(), s, mut, nil, WithFailFast(), WithCollectAllErrors())
+	var sliceErr *SliceError
+	if !errors.As(err, &sliceErr) {
+		t.Fatalf("Slice(WithFailFast(), WithCollectAllErrors()) = %v, want *SliceError", err)
+	}
+	if len(sliceErr.Errs) != 1 {
+		t.Fatalf("SliceError.Errs = %d entries, want 1 (fail-fast cancellation noise should be filtered): %v", len(sliceErr.Errs), sliceErr.Errs)
+	}
+	if !errors.Is(sliceErr.Errs[0].Err, wantErr) {
+		t.Fatalf("SliceError.Errs[0].Err = %v, want wrapping %v", sliceErr.Errs[0].Err, wantErr)
+	}
+}
+
+// TestWithContextCancelsDerivedContextWhenDone ensures SliceWithContext/ResultSliceWithContext
+// release their derived context's resources once the returned function returns, even when
+// WithFailFast is never used (and so never fires), matching errgroup.Group.Wait()'s
+// unconditional cancel. Leaving this uncalled leaks an entry in the parent context's
+// children for the life of the parent.
+func TestWithContextCancelsDerivedContextWhenDone(t *testing.T) {
+	noopMut := func(ctx context.Context, i int) (int, error) { return i, nil }
+
+	t.Run("Slice", func(t *testing.T) {
+		derived, slice := SliceWithContext[int](context.Background())
+		if err := slice([]int{1, 2, 3}, noopMut, nil); err != nil {
+			t.Fatalf("slice(...) = %v, want nil", err)
+		}
+		if err := derived.Err(); !errors.Is(err, context.Canceled) {
+			t.Fatalf("derived.Err() after slice returned = %v, want context.Canceled (leaked, uncanceled derived context)", err)
+		}
+	})
+
+	t.Run("ResultSlice", func(t *testing.T) {
+		derived, resultSlice := ResultSliceWithContext[int, int](context.Background())
+		if _, err := resultSlice([]int{1, 2, 3}, noopMut, nil); err != nil {
+			t.Fatalf("resultSlice(...) = %v, want nil", err)
+		}
+		if err := derived.Err(); !errors.Is(err, context.Canceled) {
+			t.Fatalf("derived.Err() after resultSlice returned = %v, want context.Canceled (leaked, uncanceled derived context)", err)
+		}
+	})
+}